@@ -0,0 +1,217 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var replicateCheckFlags = []cli.Flag{}
+
+var replicateCheckCmd = cli.Command{
+	Name:         "check",
+	Usage:        "validate replication configuration for a bucket",
+	Action:       mainReplicateCheck,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(globalFlags, replicateCheckFlags...),
+	CustomHelpTemplate: `NAME:
+   {{.HelpName}} - {{.Usage}}
+
+USAGE:
+   {{.HelpName}} TARGET
+
+FLAGS:
+   {{range .VisibleFlags}}{{.}}
+   {{end}}
+EXAMPLES:
+  1. Validate replication configuration for bucket "mybucket" on alias "myminio" against every configured remote target.
+   {{.Prompt}} {{.HelpName}} myminio/mybucket
+`,
+}
+
+// checkReplicateCheckSyntax - validate all the passed arguments
+func checkReplicateCheckSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "check", 1) // last argument is exit code
+	}
+}
+
+// replicateCheckResult is the outcome of a single check against a single
+// remote target, e.g. "versioning" on "arn:minio:replication::xxx:mybucket".
+type replicateCheckResult struct {
+	Check       string `json:"check"`
+	TargetArn   string `json:"targetArn"`
+	Status      string `json:"status"` // pass, fail, warn
+	Detail      string `json:"detail,omitempty"`
+	Remediation string `json:"remediation,omitempty"`
+}
+
+type replicateCheckMessage struct {
+	Op      string                 `json:"op"`
+	URL     string                 `json:"url"`
+	Status  string                 `json:"status"`
+	Results []replicateCheckResult `json:"results"`
+}
+
+func (r replicateCheckMessage) JSON() string {
+	r.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(r, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+func (r replicateCheckMessage) String() string {
+	var msg string
+	for _, res := range r.Results {
+		color := "replicateCheckPass"
+		switch res.Status {
+		case "fail":
+			color = "replicateCheckFail"
+		case "warn":
+			color = "replicateCheckWarn"
+		}
+		line := fmt.Sprintf("[%s] %-45s target=%s", res.Status, res.Check, res.TargetArn)
+		if res.Detail != "" {
+			line += fmt.Sprintf(" - %s", res.Detail)
+		}
+		if res.Status != "pass" && res.Remediation != "" {
+			line += fmt.Sprintf("\n    remediation: %s", res.Remediation)
+		}
+		msg += console.Colorize(color, line) + "\n"
+	}
+	return strings.TrimSuffix(msg, "\n")
+}
+
+// replicationCheckTarget is the per-target outcome of CheckBucketReplication,
+// as reported by the MinIO `?replication-check=` endpoint.
+type replicationCheckTarget struct {
+	Arn                       string
+	Reachable                 bool
+	VersioningEnabled         bool
+	PermissionsOK             bool
+	MissingPermissions        string
+	ObjectLockEnabledOnSource bool
+	ObjectLockParity          bool
+	ArnConfigured             bool
+}
+
+// runReplicationChecks exercises CheckBucketReplication for every configured
+// remote target of the client's bucket and reduces the response into a flat list of
+// per-check results that are easy to render as a table or as JSON.
+func runReplicationChecks(ctx context.Context, client Client, args []string) []replicateCheckResult {
+	report, err := client.CheckBucketReplication(ctx)
+	fatalIf(err.Trace(args...), "Unable to check replication configuration")
+
+	var results []replicateCheckResult
+	for _, t := range report.Targets {
+		results = append(results, replicateCheckResult{
+			Check:       "target reachable / credentials valid",
+			TargetArn:   t.Arn,
+			Status:      statusFromBool(t.Reachable),
+			Remediation: "verify the remote target alias and credentials with `mc admin bucket remote ls`",
+		})
+		results = append(results, replicateCheckResult{
+			Check:       "versioning enabled on source and destination",
+			TargetArn:   t.Arn,
+			Status:      statusFromBool(t.VersioningEnabled),
+			Remediation: "enable versioning on both buckets with `mc version enable`",
+		})
+		results = append(results, replicateCheckResult{
+			Check:       "replication permissions present",
+			TargetArn:   t.Arn,
+			Status:      statusFromBool(t.PermissionsOK),
+			Detail:      t.MissingPermissions,
+			Remediation: "grant s3:ReplicateObject, s3:ReplicateDelete, s3:ReplicateTags, s3:GetReplicationConfiguration, s3:GetObjectVersionForReplication",
+		})
+		results = append(results, replicateCheckResult{
+			Check:       "object lock parity",
+			TargetArn:   t.Arn,
+			Status:      statusForObjectLockParity(t),
+			Remediation: "enable object locking on the remote bucket to match the source",
+		})
+		results = append(results, replicateCheckResult{
+			Check:       "rule ARN maps to a configured target",
+			TargetArn:   t.Arn,
+			Status:      statusFromBool(t.ArnConfigured),
+			Remediation: "re-add the remote target with `mc admin bucket remote add` and update the rule's ARN",
+		})
+	}
+	return results
+}
+
+func statusFromBool(ok bool) string {
+	if ok {
+		return "pass"
+	}
+	return "fail"
+}
+
+// statusForObjectLockParity only fails the check when object lock actually
+// needs to match: if the source bucket doesn't have object lock enabled
+// there's nothing to verify on the remote, so it's surfaced as a warning
+// rather than a hard failure or a silent pass.
+func statusForObjectLockParity(t replicationCheckTarget) string {
+	if !t.ObjectLockEnabledOnSource {
+		return "warn"
+	}
+	return statusFromBool(t.ObjectLockParity)
+}
+
+func mainReplicateCheck(cliCtx *cli.Context) error {
+	ctx, cancelReplicateCheck := context.WithCancel(globalContext)
+	defer cancelReplicateCheck()
+
+	console.SetColor("replicateCheckPass", color.New(color.FgGreen))
+	console.SetColor("replicateCheckWarn", color.New(color.FgYellow))
+	console.SetColor("replicateCheckFail", color.New(color.FgRed))
+
+	checkReplicateCheckSyntax(cliCtx)
+
+	args := cliCtx.Args()
+	aliasedURL := args.Get(0)
+
+	client, err := newClient(aliasedURL)
+	fatalIf(err, "Unable to initialize connection.")
+
+	results := runReplicationChecks(ctx, client, args)
+
+	printMsg(replicateCheckMessage{
+		Op:      "check",
+		URL:     aliasedURL,
+		Results: results,
+	})
+
+	for _, res := range results {
+		if res.Status == "fail" {
+			// Misconfiguration was found: exit non-zero so this is
+			// scriptable, e.g. `mc replicate check ... || alert`.
+			os.Exit(1)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/madmin-go/v2"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var replicateListCmd = cli.Command{
+	Name:         "ls",
+	Usage:        "list remote replication targets configured on a bucket",
+	Action:       mainReplicateList,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+   {{.HelpName}} - {{.Usage}}
+
+USAGE:
+   {{.HelpName}} TARGET
+
+FLAGS:
+   {{range .VisibleFlags}}{{.}}
+   {{end}}
+EXAMPLES:
+  1. List remote replication targets for bucket "mybucket" on alias "myminio".
+   {{.Prompt}} {{.HelpName}} myminio/mybucket
+`,
+}
+
+func checkReplicateListSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "ls", 1) // last argument is exit code
+	}
+}
+
+func replicationModeOf(t madmin.BucketTarget) string {
+	if t.ReplicationSync {
+		return "sync"
+	}
+	return "async"
+}
+
+type replicateListMessage struct {
+	Op      string                `json:"op"`
+	URL     string                `json:"url"`
+	Status  string                `json:"status"`
+	Targets []madmin.BucketTarget `json:"targets"`
+}
+
+func (r replicateListMessage) JSON() string {
+	r.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(r, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+func (r replicateListMessage) String() string {
+	var msg string
+	for _, t := range r.Targets {
+		msg += console.Colorize("replicateListMessage", fmt.Sprintf(
+			"%s -> %s  mode=%s  healthcheck=%s  bandwidth=%d",
+			r.URL, t.Arn, replicationModeOf(t), t.HealthCheckDuration, t.BandwidthLimit)) + "\n"
+	}
+	return msg
+}
+
+func mainReplicateList(cliCtx *cli.Context) error {
+	ctx, cancelReplicateList := context.WithCancel(globalContext)
+	defer cancelReplicateList()
+
+	console.SetColor("replicateListMessage", color.New(color.FgGreen))
+
+	checkReplicateListSyntax(cliCtx)
+
+	args := cliCtx.Args()
+	aliasedURL := args.Get(0)
+
+	client, err := newClient(aliasedURL)
+	fatalIf(err, "Unable to initialize connection.")
+
+	targets, err := client.ListReplicationTargets(ctx)
+	fatalIf(err.Trace(args...), "Unable to list remote replication targets")
+
+	printMsg(replicateListMessage{
+		Op:      "ls",
+		URL:     aliasedURL,
+		Targets: targets,
+	})
+	return nil
+}
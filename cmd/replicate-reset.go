@@ -21,15 +21,16 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/fatih/color"
 	"github.com/minio/cli"
 	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/duration"
 	"github.com/minio/mc/pkg/probe"
 	"github.com/minio/minio-go/v7/pkg/replication"
 	"github.com/minio/pkg/console"
-	"maze.io/x/duration"
 )
 
 var replicateResetFlags = []cli.Flag{
@@ -39,7 +40,12 @@ var replicateResetFlags = []cli.Flag{
 	},
 	cli.StringFlag{
 		Name:  "remote-bucket",
-		Usage: "remote bucket ARN",
+		Usage: "comma-separated remote bucket ARN(s) to resync, defaults to every configured target",
+	},
+	cli.IntFlag{
+		Name:  "parallel",
+		Usage: "number of targets to resync concurrently",
+		Value: 4,
 	},
 }
 
@@ -51,6 +57,11 @@ var replicateResetCmd = cli.Command{
 	OnUsageError: onUsageError,
 	Before:       setGlobalsFromContext,
 	Flags:        append(globalFlags, replicateResetFlags...),
+	Subcommands: []cli.Command{
+		replicateResyncStatusCmd,
+		replicateResyncCancelCmd,
+		replicateResyncJobsCmd,
+	},
 	CustomHelpTemplate: `NAME:
    {{.HelpName}} - {{.Usage}}
 
@@ -66,6 +77,18 @@ EXAMPLES:
 
   2. Re-replicate all objects older than 60 days in bucket "mybucket" for remote bucket target.
    {{.Prompt}} {{.HelpName}} myminio/mybucket --older-than 60d --remote-bucket "arn:minio:replication::xxx:mybucket"
+
+  3. Watch progress of an ongoing resync, or cancel it.
+   {{.Prompt}} {{.HelpName}} status myminio/mybucket --remote-bucket "arn:minio:replication::xxx:mybucket" --watch
+   {{.Prompt}} {{.HelpName}} cancel myminio/mybucket --remote-bucket "arn:minio:replication::xxx:mybucket"
+
+  4. Resync every configured remote target for bucket "mybucket", 2 at a time, and leave a job manifest behind.
+   {{.Prompt}} {{.HelpName}} myminio/mybucket --parallel 2
+
+  5. Re-attach to a resync job later, or clean up manifests for jobs that finished.
+   {{.Prompt}} {{.HelpName}} jobs list
+   {{.Prompt}} {{.HelpName}} jobs resume ~/.mc/replicate-resync/myminio-mybucket-1690000000.json
+   {{.Prompt}} {{.HelpName}} jobs prune
 `,
 }
 
@@ -74,9 +97,20 @@ func checkReplicateResetSyntax(ctx *cli.Context) {
 	if len(ctx.Args()) != 1 {
 		cli.ShowCommandHelpAndExit(ctx, "reset", 1) // last argument is exit code
 	}
-	if ctx.String("remote-bucket") == "" {
-		fatal(errDummy().Trace(), "--remote-bucket flag needs to be specified.")
+	if ctx.Int("parallel") < 1 {
+		fatal(errDummy().Trace(), "--parallel must be at least 1.")
+	}
+}
+
+// splitAliasedURL splits "myminio/mybucket" into its alias and bucket parts,
+// which is what the resync job manifest is keyed and named on.
+func splitAliasedURL(aliasedURL string) (alias, bucket string) {
+	parts := strings.SplitN(aliasedURL, "/", 2)
+	alias = parts[0]
+	if len(parts) > 1 {
+		bucket = parts[1]
 	}
+	return alias, bucket
 }
 
 type replicateResetMessage struct {
@@ -102,6 +136,31 @@ func (r replicateResetMessage) String() string {
 
 }
 
+// resolveResyncTargets returns the ARNs a resync should run against: the
+// explicit --remote-bucket list if one was given, otherwise every target
+// currently configured on the bucket.
+func resolveResyncTargets(ctx context.Context, client Client, cliCtx *cli.Context, args []string) []string {
+	if arns := cliCtx.String("remote-bucket"); arns != "" {
+		var out []string
+		for _, arn := range strings.Split(arns, ",") {
+			if arn = strings.TrimSpace(arn); arn != "" {
+				out = append(out, arn)
+			}
+		}
+		return out
+	}
+	targets, err := client.ListReplicationTargets(ctx)
+	fatalIf(err.Trace(args...), "Unable to list remote replication targets")
+	var out []string
+	for _, t := range targets {
+		out = append(out, t.Arn)
+	}
+	if len(out) == 0 {
+		fatal(errDummy().Trace(args...), "No remote replication targets are configured on this bucket.")
+	}
+	return out
+}
+
 func mainReplicateReset(cliCtx *cli.Context) error {
 	ctx, cancelReplicateReset := context.WithCancel(globalContext)
 	defer cancelReplicateReset()
@@ -116,28 +175,78 @@ func mainReplicateReset(cliCtx *cli.Context) error {
 	// Create a new Client
 	client, err := newClient(aliasedURL)
 	fatalIf(err, "Unable to initialize connection.")
-	var olderThanStr string
+	// Client.ResetReplication takes a time.Duration, not an absolute
+	// cutoff, so ToDuration is what we can actually hand it; Duration.Before
+	// is still the right helper once a caller can pass a cutoff instead
+	// (see pkg/duration).
 	var olderThan time.Duration
-	if cliCtx.IsSet("older-than") {
-		olderThanStr = cliCtx.String("older-than")
-		if olderThanStr != "" {
-			days, e := duration.ParseDuration(olderThanStr)
-			if e != nil || !strings.ContainsAny(olderThanStr, "dwy") {
-				fatalIf(probe.NewError(e), "Unable to parse older-than=`"+olderThanStr+"`.")
-			}
-			if days == 0 {
-				fatalIf(probe.NewError(e), "older-than cannot be set to zero")
-			}
-			olderThan = time.Duration(days.Days())
+	if olderThanStr := cliCtx.String("older-than"); olderThanStr != "" {
+		d, e := duration.ParseDuration(olderThanStr)
+		if e != nil {
+			fatalIf(probe.NewError(e), "Unable to parse older-than=`"+olderThanStr+"`.")
 		}
+		olderThan = d.ToDuration()
 	}
 
-	rinfo, err := client.ResetReplication(ctx, olderThan, cliCtx.String("remote-bucket"))
-	fatalIf(err.Trace(args...), "Unable to reset replication")
-	printMsg(replicateResetMessage{
-		Op:                "status",
-		URL:               aliasedURL,
-		ResyncTargetsInfo: rinfo,
-	})
+	arns := resolveResyncTargets(ctx, client, cliCtx, args)
+
+	type resetResult struct {
+		arn   string
+		rinfo replication.ResyncTargetsInfo
+		err   *probe.Error
+	}
+	results := make([]resetResult, len(arns))
+	sem := make(chan struct{}, cliCtx.Int("parallel"))
+	var wg sync.WaitGroup
+	for i, arn := range arns {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, arn string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			rinfo, rerr := client.ResetReplication(ctx, olderThan, arn)
+			results[i] = resetResult{arn: arn, rinfo: rinfo, err: rerr}
+		}(i, arn)
+	}
+	wg.Wait()
+
+	alias, bucket := splitAliasedURL(aliasedURL)
+	var jobTargets []resyncJobTarget
+	var failed []string
+	for _, res := range results {
+		if res.err != nil {
+			errorIf(res.err.Trace(args...), "Unable to reset replication for target "+res.arn)
+			failed = append(failed, res.arn)
+			continue
+		}
+		var resetID string
+		if len(res.rinfo.Targets) == 1 {
+			resetID = res.rinfo.Targets[0].ResetID
+		}
+		jobTargets = append(jobTargets, resyncJobTarget{
+			Arn:       res.arn,
+			ResetID:   resetID,
+			StartTime: time.Now(),
+		})
+		printMsg(replicateResetMessage{
+			Op:                "status",
+			URL:               aliasedURL,
+			TargetArn:         res.arn,
+			ResyncTargetsInfo: res.rinfo,
+		})
+	}
+
+	// Persist a manifest for every target that did start, even if some
+	// targets in this same invocation failed - those resyncs are now
+	// running server-side and must stay trackable.
+	if len(jobTargets) > 0 {
+		manifest, merr := newResyncJobManifest(alias, bucket, jobTargets)
+		fatalIf(merr, "Unable to persist resync job manifest")
+		console.Println(console.Colorize("replicateResetMessage", fmt.Sprintf("Resync job manifest: %s", manifest.path)))
+	}
+
+	if len(failed) > 0 {
+		fatal(errDummy().Trace(failed...), fmt.Sprintf("Failed to start resync for %d of %d target(s): %s", len(failed), len(arns), strings.Join(failed, ", ")))
+	}
 	return nil
 }
@@ -0,0 +1,293 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/pkg/console"
+)
+
+var replicateResyncJobsCmd = cli.Command{
+	Name:         "jobs",
+	Usage:        "manage local resync job manifests",
+	Action:       mainReplicateResyncJobs,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	Subcommands: []cli.Command{
+		replicateResyncJobsListCmd,
+		replicateResyncJobsShowCmd,
+		replicateResyncJobsResumeCmd,
+		replicateResyncJobsPruneCmd,
+	},
+	CustomHelpTemplate: `NAME:
+   {{.HelpName}} - {{.Usage}}
+
+USAGE:
+   {{.HelpName}} COMMAND
+
+COMMANDS:
+   {{range .VisibleCommands}}{{join .Names ", "}}{{"\t"}}{{.Usage}}
+   {{end}}`,
+}
+
+func mainReplicateResyncJobs(ctx *cli.Context) error {
+	commandNotFound(ctx, replicateResyncJobsCmd)
+	return nil
+}
+
+var replicateResyncJobsListCmd = cli.Command{
+	Name:         "list",
+	Usage:        "list locally tracked resync jobs",
+	Action:       mainReplicateResyncJobsList,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+}
+
+var replicateResyncJobsShowCmd = cli.Command{
+	Name:         "show",
+	Usage:        "show the last known progress of a resync job",
+	Action:       mainReplicateResyncJobsShow,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+	CustomHelpTemplate: `NAME:
+   {{.HelpName}} - {{.Usage}}
+
+USAGE:
+   {{.HelpName}} JOB_MANIFEST
+`,
+}
+
+var replicateResyncJobsResumeCmd = cli.Command{
+	Name:         "resume",
+	Usage:        "re-attach to a resync job and poll it until it completes",
+	Action:       mainReplicateResyncJobsResume,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags: append(globalFlags, cli.DurationFlag{
+		Name:  "interval",
+		Usage: "polling interval",
+		Value: 5 * time.Second,
+	}),
+	CustomHelpTemplate: `NAME:
+   {{.HelpName}} - {{.Usage}}
+
+USAGE:
+   {{.HelpName}} JOB_MANIFEST
+`,
+}
+
+var replicateResyncJobsPruneCmd = cli.Command{
+	Name:         "prune",
+	Usage:        "remove manifests for resync jobs that have completed",
+	Action:       mainReplicateResyncJobsPrune,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        globalFlags,
+}
+
+// replicateResyncJobSummary is one row of `replicate resync jobs list`.
+type replicateResyncJobSummary struct {
+	Path    string    `json:"path"`
+	Alias   string    `json:"alias"`
+	Bucket  string    `json:"bucket"`
+	Targets int       `json:"targets"`
+	Created time.Time `json:"created"`
+	Status  string    `json:"status"` // ongoing, done
+}
+
+type replicateResyncJobsListMessage struct {
+	Op     string                      `json:"op"`
+	Status string                      `json:"status"`
+	Jobs   []replicateResyncJobSummary `json:"jobs"`
+}
+
+func (r replicateResyncJobsListMessage) JSON() string {
+	r.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(r, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+func (r replicateResyncJobsListMessage) String() string {
+	var msg string
+	for _, j := range r.Jobs {
+		msg += fmt.Sprintf("%s  %s/%s  targets=%d  created=%s  status=%s\n",
+			j.Path, j.Alias, j.Bucket, j.Targets, j.Created.Format(time.RFC3339), j.Status)
+	}
+	return console.Colorize("replicateResyncJobsMessage", strings.TrimSuffix(msg, "\n"))
+}
+
+func mainReplicateResyncJobsList(cliCtx *cli.Context) error {
+	console.SetColor("replicateResyncJobsMessage", color.New(color.FgGreen))
+
+	manifests, err := listResyncJobManifests()
+	fatalIf(err, "Unable to list resync jobs")
+
+	var jobs []replicateResyncJobSummary
+	for _, m := range manifests {
+		status := "ongoing"
+		if m.done() {
+			status = "done"
+		}
+		jobs = append(jobs, replicateResyncJobSummary{
+			Path:    m.path,
+			Alias:   m.Alias,
+			Bucket:  m.Bucket,
+			Targets: len(m.Targets),
+			Created: m.CreatedAt,
+			Status:  status,
+		})
+	}
+	printMsg(replicateResyncJobsListMessage{Op: "list", Jobs: jobs})
+	return nil
+}
+
+func checkResyncJobManifestArg(ctx *cli.Context, cmdName string) string {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, cmdName, 1) // last argument is exit code
+	}
+	return ctx.Args().Get(0)
+}
+
+type replicateResyncJobMessage struct {
+	Op       string             `json:"op"`
+	Status   string             `json:"status"`
+	Manifest *resyncJobManifest `json:"manifest"`
+}
+
+func (r replicateResyncJobMessage) JSON() string {
+	r.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(r, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+func (r replicateResyncJobMessage) String() string {
+	m := r.Manifest
+	msg := fmt.Sprintf("Resync job for %s/%s (created %s)\n", m.Alias, m.Bucket, m.CreatedAt.Format(time.RFC3339))
+	for _, t := range m.Targets {
+		msg += fmt.Sprintf("  target=%s resetID=%s status=%s replicated=%d failed=%d\n",
+			t.Arn, t.ResetID, t.LastObserved.ResyncStatus, t.LastObserved.ReplicatedCount, t.LastObserved.FailedCount)
+	}
+	return console.Colorize("replicateResyncJobMessage", msg)
+}
+
+func mainReplicateResyncJobsShow(cliCtx *cli.Context) error {
+	path := checkResyncJobManifestArg(cliCtx, "show")
+	m, err := loadResyncJobManifest(path)
+	fatalIf(err, "Unable to load resync job manifest")
+	printMsg(replicateResyncJobMessage{Op: "show", Manifest: m})
+	return nil
+}
+
+// pollResyncJob re-attaches to a job's alias/bucket and refreshes every
+// target's LastObserved progress from the server, saving the manifest back
+// to disk after every poll so a restart of mc can resume from where it left off.
+func pollResyncJob(ctx context.Context, m *resyncJobManifest) *probe.Error {
+	client, err := newClient(m.Alias + "/" + m.Bucket)
+	if err != nil {
+		return err
+	}
+	for i, t := range m.Targets {
+		target, err := fetchResyncTarget(ctx, client, t.Arn)
+		if err != nil {
+			errorIf(err.Trace(t.Arn), fmt.Sprintf("Unable to refresh resync status for target %s, keeping last known progress", t.Arn))
+			continue
+		}
+		m.Targets[i].LastObserved = target
+		m.Targets[i].LastPolled = time.Now()
+	}
+	return m.save()
+}
+
+func mainReplicateResyncJobsResume(cliCtx *cli.Context) error {
+	ctx, cancel := context.WithCancel(globalContext)
+	defer cancel()
+
+	console.SetColor("replicateResyncJobMessage", color.New(color.FgGreen))
+
+	path := checkResyncJobManifestArg(cliCtx, "resume")
+	m, err := loadResyncJobManifest(path)
+	fatalIf(err, "Unable to load resync job manifest")
+
+	for {
+		err := pollResyncJob(ctx, m)
+		fatalIf(err, "Unable to refresh resync job progress")
+
+		printMsg(replicateResyncJobMessage{Op: "resume", Manifest: m})
+
+		if m.done() {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(cliCtx.Duration("interval")):
+		}
+	}
+	return nil
+}
+
+type replicateResyncJobsPruneMessage struct {
+	Op     string `json:"op"`
+	Status string `json:"status"`
+	Pruned int    `json:"pruned"`
+}
+
+func (r replicateResyncJobsPruneMessage) JSON() string {
+	r.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(r, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+func (r replicateResyncJobsPruneMessage) String() string {
+	return console.Colorize("replicateResyncJobsMessage", fmt.Sprintf("Pruned %d completed resync job(s)", r.Pruned))
+}
+
+func mainReplicateResyncJobsPrune(cliCtx *cli.Context) error {
+	console.SetColor("replicateResyncJobsMessage", color.New(color.FgGreen))
+
+	manifests, err := listResyncJobManifests()
+	fatalIf(err, "Unable to list resync jobs")
+
+	var pruned int
+	for _, m := range manifests {
+		if !m.done() {
+			continue
+		}
+		if e := os.Remove(m.path); e != nil {
+			continue
+		}
+		pruned++
+	}
+	printMsg(replicateResyncJobsPruneMessage{Op: "prune", Pruned: pruned})
+	return nil
+}
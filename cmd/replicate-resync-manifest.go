@@ -0,0 +1,151 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/minio/mc/pkg/probe"
+)
+
+// resyncJobTarget tracks one remote target's resync within a job manifest,
+// along with the last progress observed the last time someone polled it.
+type resyncJobTarget struct {
+	Arn          string               `json:"arn"`
+	ResetID      string               `json:"resetID"`
+	StartTime    time.Time            `json:"startTime"`
+	LastObserved resyncTargetProgress `json:"lastObserved,omitempty"`
+	LastPolled   time.Time            `json:"lastPolled,omitempty"`
+}
+
+// resyncJobManifest is a resumable record of a multi-target `mc replicate
+// resync` invocation. It is persisted under
+// ~/.mc/replicate-resync/<alias>-<bucket>-<createdAt-unix>.json so a user
+// can walk away from a long-running resync and later re-attach to it, even
+// across restarts of mc itself.
+type resyncJobManifest struct {
+	Alias     string            `json:"alias"`
+	Bucket    string            `json:"bucket"`
+	CreatedAt time.Time         `json:"createdAt"`
+	Targets   []resyncJobTarget `json:"targets"`
+	path      string
+}
+
+// replicateResyncJobsDir returns ~/.mc/replicate-resync, creating nothing
+// itself; callers that need the directory to exist create it on write.
+func replicateResyncJobsDir() (string, *probe.Error) {
+	home, e := os.UserHomeDir()
+	if e != nil {
+		return "", probe.NewError(e)
+	}
+	return filepath.Join(home, globalMCConfigDir, "replicate-resync"), nil
+}
+
+// newResyncJobManifest creates and persists a fresh manifest for alias/bucket.
+func newResyncJobManifest(alias, bucket string, targets []resyncJobTarget) (*resyncJobManifest, *probe.Error) {
+	dir, err := replicateResyncJobsDir()
+	if err != nil {
+		return nil, err
+	}
+	if e := os.MkdirAll(dir, 0o700); e != nil {
+		return nil, probe.NewError(e)
+	}
+	now := time.Now()
+	m := &resyncJobManifest{
+		Alias:     alias,
+		Bucket:    bucket,
+		CreatedAt: now,
+		Targets:   targets,
+		path:      filepath.Join(dir, fmt.Sprintf("%s-%s-%d.json", alias, bucket, now.Unix())),
+	}
+	return m, m.save()
+}
+
+func (m *resyncJobManifest) save() *probe.Error {
+	b, e := json.MarshalIndent(m, "", " ")
+	if e != nil {
+		return probe.NewError(e)
+	}
+	if e := os.WriteFile(m.path, b, 0o600); e != nil {
+		return probe.NewError(e)
+	}
+	return nil
+}
+
+// loadResyncJobManifest reads back a manifest previously written by
+// newResyncJobManifest, keeping track of the file it was loaded from so it
+// can be saved back to the same place.
+func loadResyncJobManifest(path string) (*resyncJobManifest, *probe.Error) {
+	b, e := os.ReadFile(path)
+	if e != nil {
+		return nil, probe.NewError(e)
+	}
+	var m resyncJobManifest
+	if e := json.Unmarshal(b, &m); e != nil {
+		return nil, probe.NewError(e)
+	}
+	m.path = path
+	return &m, nil
+}
+
+// listResyncJobManifests returns every manifest under the resync jobs
+// directory, most recently created first.
+func listResyncJobManifests() ([]*resyncJobManifest, *probe.Error) {
+	dir, err := replicateResyncJobsDir()
+	if err != nil {
+		return nil, err
+	}
+	entries, e := os.ReadDir(dir)
+	if e != nil {
+		if os.IsNotExist(e) {
+			return nil, nil
+		}
+		return nil, probe.NewError(e)
+	}
+	var manifests []*resyncJobManifest
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		m, err := loadResyncJobManifest(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		manifests = append(manifests, m)
+	}
+	sort.Slice(manifests, func(i, j int) bool {
+		return manifests[i].CreatedAt.After(manifests[j].CreatedAt)
+	})
+	return manifests, nil
+}
+
+// done reports whether every target in the manifest has finished resyncing,
+// based on the last progress observed.
+func (m *resyncJobManifest) done() bool {
+	for _, t := range m.Targets {
+		if t.LastObserved.ResyncStatus == "Ongoing" || t.LastObserved.ResyncStatus == "" {
+			return false
+		}
+	}
+	return true
+}
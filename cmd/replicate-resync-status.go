@@ -0,0 +1,247 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/minio/cli"
+	json "github.com/minio/colorjson"
+	"github.com/minio/mc/pkg/probe"
+	"github.com/minio/minio-go/v7/pkg/replication"
+	"github.com/minio/pkg/console"
+)
+
+var replicateResyncStatusFlags = []cli.Flag{
+	cli.StringFlag{
+		Name:  "remote-bucket",
+		Usage: "remote bucket ARN",
+	},
+	cli.BoolFlag{
+		Name:  "watch, w",
+		Usage: "watch the resync progress until it completes",
+	},
+	cli.DurationFlag{
+		Name:  "interval",
+		Usage: "polling interval when --watch is set",
+		Value: 5 * time.Second,
+	},
+}
+
+var replicateResyncStatusCmd = cli.Command{
+	Name:         "status",
+	Usage:        "show status of an ongoing replication resync",
+	Action:       mainReplicateResyncStatus,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(globalFlags, replicateResyncStatusFlags...),
+	CustomHelpTemplate: `NAME:
+   {{.HelpName}} - {{.Usage}}
+
+USAGE:
+   {{.HelpName}} TARGET
+
+FLAGS:
+   {{range .VisibleFlags}}{{.}}
+   {{end}}
+EXAMPLES:
+  1. Show replication resync status for bucket "mybucket" for alias "myminio" for remote target.
+   {{.Prompt}} {{.HelpName}} myminio/mybucket --remote-bucket "arn:minio:replication::xxx:mybucket"
+
+  2. Watch replication resync progress until it completes.
+   {{.Prompt}} {{.HelpName}} myminio/mybucket --remote-bucket "arn:minio:replication::xxx:mybucket" --watch
+`,
+}
+
+var replicateResyncCancelCmd = cli.Command{
+	Name:         "cancel",
+	Usage:        "cancel an ongoing replication resync",
+	Action:       mainReplicateResyncCancel,
+	OnUsageError: onUsageError,
+	Before:       setGlobalsFromContext,
+	Flags:        append(globalFlags, cli.StringFlag{Name: "remote-bucket", Usage: "remote bucket ARN"}),
+	CustomHelpTemplate: `NAME:
+   {{.HelpName}} - {{.Usage}}
+
+USAGE:
+   {{.HelpName}} TARGET
+
+FLAGS:
+   {{range .VisibleFlags}}{{.}}
+   {{end}}
+EXAMPLES:
+  1. Cancel an ongoing replication resync for bucket "mybucket" for alias "myminio" for remote target.
+   {{.Prompt}} {{.HelpName}} myminio/mybucket --remote-bucket "arn:minio:replication::xxx:mybucket"
+`,
+}
+
+// checkReplicateResyncStatusSyntax - validate all the passed arguments
+func checkReplicateResyncStatusSyntax(ctx *cli.Context) {
+	if len(ctx.Args()) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "status", 1) // last argument is exit code
+	}
+	if ctx.String("remote-bucket") == "" {
+		fatal(errDummy().Trace(), "--remote-bucket flag needs to be specified.")
+	}
+}
+
+// resyncTargetProgress augments replication.ResyncTarget with the
+// scanned/total object counters the server reports while the bucket walk
+// backing a resync is still in progress. ResyncTarget on its own only
+// tracks objects that have already been attempted (replicated or failed),
+// which makes for a misleading completion percentage early in a resync.
+type resyncTargetProgress struct {
+	replication.ResyncTarget
+	TotalCount uint64    `json:"totalCount"`
+	LastUpdate time.Time `json:"lastUpdate"`
+}
+
+type replicateResyncStatusMessage struct {
+	Op        string               `json:"op"`
+	URL       string               `json:"url"`
+	TargetArn string               `json:"targetArn"`
+	Status    string               `json:"status"`
+	Target    resyncTargetProgress `json:"target"`
+}
+
+func (r replicateResyncStatusMessage) JSON() string {
+	r.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(r, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+// resyncCompletionPct reports progress through the whole resync walk, i.e.
+// ResyncedCount / TotalCount where ResyncedCount is every object the walk
+// has already attempted (replicated or failed), not just the ones that
+// succeeded - otherwise a resync that's fully attempted but has some
+// failures would never show 100%, contradicting a "Completed" status.
+func resyncCompletionPct(t resyncTargetProgress) float64 {
+	if t.TotalCount == 0 {
+		return 0
+	}
+	return float64(t.ReplicatedCount+t.FailedCount) / float64(t.TotalCount) * 100
+}
+
+func (r replicateResyncStatusMessage) String() string {
+	t := r.Target
+	msg := fmt.Sprintf("Resync status for %s (target %s)\n", r.URL, r.TargetArn)
+	msg += fmt.Sprintf("   Status:            %s\n", t.ResyncStatus)
+	msg += fmt.Sprintf("   Objects scanned:    %d\n", t.TotalCount)
+	msg += fmt.Sprintf("   Objects replicated: %d\n", t.ReplicatedCount)
+	msg += fmt.Sprintf("   Bytes replicated:   %d\n", t.ReplicatedSize)
+	msg += fmt.Sprintf("   Objects failed:     %d\n", t.FailedCount)
+	msg += fmt.Sprintf("   Started:            %s\n", t.StartTime)
+	msg += fmt.Sprintf("   Last updated:       %s\n", t.LastUpdate)
+	msg += fmt.Sprintf("   Completion:         %.1f%%", resyncCompletionPct(t))
+	return console.Colorize("replicateResyncStatusMessage", msg)
+}
+
+func fetchResyncTarget(ctx context.Context, client Client, arn string) (resyncTargetProgress, *probe.Error) {
+	progress, err := client.ReplicationResyncStatus(ctx, arn)
+	if err != nil {
+		return resyncTargetProgress{}, err
+	}
+	return progress, nil
+}
+
+func mainReplicateResyncStatus(cliCtx *cli.Context) error {
+	ctx, cancelReplicateResyncStatus := context.WithCancel(globalContext)
+	defer cancelReplicateResyncStatus()
+
+	console.SetColor("replicateResyncStatusMessage", color.New(color.FgGreen))
+
+	checkReplicateResyncStatusSyntax(cliCtx)
+
+	args := cliCtx.Args()
+	aliasedURL := args.Get(0)
+	arn := cliCtx.String("remote-bucket")
+
+	client, err := newClient(aliasedURL)
+	fatalIf(err, "Unable to initialize connection.")
+
+	for {
+		target, err := fetchResyncTarget(ctx, client, arn)
+		fatalIf(err.Trace(args...), "Unable to fetch replication resync status")
+
+		printMsg(replicateResyncStatusMessage{
+			Op:        "status",
+			URL:       aliasedURL,
+			TargetArn: arn,
+			Target:    target,
+		})
+
+		if !cliCtx.Bool("watch") || target.ResyncStatus != "Ongoing" {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(cliCtx.Duration("interval")):
+		}
+	}
+	return nil
+}
+
+type replicateResyncCancelMessage struct {
+	Op        string `json:"op"`
+	URL       string `json:"url"`
+	TargetArn string `json:"targetArn"`
+	Status    string `json:"status"`
+}
+
+func (r replicateResyncCancelMessage) JSON() string {
+	r.Status = "success"
+	jsonMessageBytes, e := json.MarshalIndent(r, "", " ")
+	fatalIf(probe.NewError(e), "Unable to marshal into JSON.")
+	return string(jsonMessageBytes)
+}
+
+func (r replicateResyncCancelMessage) String() string {
+	return console.Colorize("replicateResyncCancelMessage", fmt.Sprintf("Replication resync cancelled for %s, target %s", r.URL, r.TargetArn))
+}
+
+func mainReplicateResyncCancel(cliCtx *cli.Context) error {
+	ctx, cancelReplicateResyncCancel := context.WithCancel(globalContext)
+	defer cancelReplicateResyncCancel()
+
+	console.SetColor("replicateResyncCancelMessage", color.New(color.FgGreen))
+
+	checkReplicateResyncStatusSyntax(cliCtx)
+
+	args := cliCtx.Args()
+	aliasedURL := args.Get(0)
+	arn := cliCtx.String("remote-bucket")
+
+	client, err := newClient(aliasedURL)
+	fatalIf(err, "Unable to initialize connection.")
+
+	err = client.ReplicationResyncCancel(ctx, arn)
+	fatalIf(err.Trace(args...), "Unable to cancel replication resync")
+
+	printMsg(replicateResyncCancelMessage{
+		Op:        "cancel",
+		URL:       aliasedURL,
+		TargetArn: arn,
+	})
+	return nil
+}
@@ -0,0 +1,133 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+// Package duration parses the "60d", "2w", "3mo", "1y" style durations
+// accepted by --older-than/--newer-than flags across mc (replicate resync,
+// ilm rules, rm, find). Unlike time.Duration, it keeps the original unit
+// around so month/year arithmetic can be done against a calendar rather
+// than assuming a fixed number of nanoseconds per day.
+package duration
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Unit identifies the calendar unit a Duration was expressed in.
+type Unit int
+
+// Supported units, ordered from shortest to longest.
+const (
+	Days Unit = iota
+	Weeks
+	Months
+	Years
+)
+
+// Duration is a parsed older-than/newer-than value, e.g. 60 Days.
+type Duration struct {
+	Amount int
+	Unit   Unit
+}
+
+// ParseError reports a malformed duration string passed to ParseDuration.
+type ParseError struct {
+	Input  string
+	Reason string
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("invalid duration %q: %s", e.Input, e.Reason)
+}
+
+// unitSuffixes is checked in order; "mo" must come before any suffix that
+// could be mistaken for it, but since none of d/w/y overlap with "mo" the
+// order here is only for readability.
+var unitSuffixes = []struct {
+	suffix string
+	unit   Unit
+}{
+	{"mo", Months},
+	{"d", Days},
+	{"w", Weeks},
+	{"y", Years},
+}
+
+// ParseDuration parses strings of the form "<n>d", "<n>w", "<n>mo" or
+// "<n>y" into a Duration. It returns a *ParseError, never a bare error
+// from strconv, so callers can type-assert on failure if they need to.
+func ParseDuration(s string) (Duration, error) {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" {
+		return Duration{}, &ParseError{Input: s, Reason: "empty duration"}
+	}
+	for _, us := range unitSuffixes {
+		if !strings.HasSuffix(trimmed, us.suffix) {
+			continue
+		}
+		numPart := strings.TrimSuffix(trimmed, us.suffix)
+		n, e := strconv.Atoi(numPart)
+		if e != nil {
+			return Duration{}, &ParseError{Input: s, Reason: "not a valid number: " + numPart}
+		}
+		if n == 0 {
+			return Duration{}, &ParseError{Input: s, Reason: "duration cannot be zero"}
+		}
+		return Duration{Amount: n, Unit: us.unit}, nil
+	}
+	return Duration{}, &ParseError{Input: s, Reason: "unrecognized suffix, expected one of d, w, mo, y"}
+}
+
+// Before returns the absolute cutoff obtained by subtracting d from t,
+// using calendar-aware month/year arithmetic. This is what servers
+// actually need for fields like ResetBeforeDate, rather than a raw
+// time.Duration that treats a month as a fixed number of nanoseconds.
+func (d Duration) Before(t time.Time) time.Time {
+	switch d.Unit {
+	case Days:
+		return t.AddDate(0, 0, -d.Amount)
+	case Weeks:
+		return t.AddDate(0, 0, -d.Amount*7)
+	case Months:
+		return t.AddDate(0, -d.Amount, 0)
+	case Years:
+		return t.AddDate(-d.Amount, 0, 0)
+	default:
+		return t
+	}
+}
+
+// ToDuration approximates d as a time.Duration using fixed-length
+// days/weeks/months/years. Prefer Before when a reference time is
+// available; months and years don't have a fixed length in nanoseconds.
+func (d Duration) ToDuration() time.Duration {
+	const day = 24 * time.Hour
+	switch d.Unit {
+	case Days:
+		return time.Duration(d.Amount) * day
+	case Weeks:
+		return time.Duration(d.Amount) * 7 * day
+	case Months:
+		return time.Duration(d.Amount) * 30 * day
+	case Years:
+		return time.Duration(d.Amount) * 365 * day
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2015-2021 MinIO, Inc.
+//
+// This file is part of MinIO Object Storage stack
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package duration
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDuration(t *testing.T) {
+	testCases := []struct {
+		input      string
+		wantAmount int
+		wantUnit   Unit
+		wantErr    bool
+	}{
+		{"60d", 60, Days, false},
+		{"2w", 2, Weeks, false},
+		{"3mo", 3, Months, false},
+		{"1y", 1, Years, false},
+		{"", 0, 0, true},
+		{"0d", 0, 0, true},
+		{"ten days", 0, 0, true},
+		{"60", 0, 0, true},
+		{"60h", 0, 0, true},
+	}
+	for _, tc := range testCases {
+		got, err := ParseDuration(tc.input)
+		if tc.wantErr {
+			if err == nil {
+				t.Errorf("ParseDuration(%q): expected error, got none", tc.input)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseDuration(%q): unexpected error: %v", tc.input, err)
+			continue
+		}
+		if got.Amount != tc.wantAmount || got.Unit != tc.wantUnit {
+			t.Errorf("ParseDuration(%q) = %+v, want {Amount:%d Unit:%d}", tc.input, got, tc.wantAmount, tc.wantUnit)
+		}
+	}
+}
+
+// TestParseDuration60DaysRegression guards against the historical bug
+// where `time.Duration(days.Days())` treated a float number of days as a
+// raw nanosecond count, turning "60d" into a 60-nanosecond cutoff instead
+// of a 60-day one.
+func TestParseDuration60DaysRegression(t *testing.T) {
+	d, err := ParseDuration("60d")
+	if err != nil {
+		t.Fatalf("ParseDuration(\"60d\") returned error: %v", err)
+	}
+	ref := time.Date(2026, 7, 27, 0, 0, 0, 0, time.UTC)
+	cutoff := d.Before(ref)
+	wantCutoff := ref.AddDate(0, 0, -60)
+	if !cutoff.Equal(wantCutoff) {
+		t.Fatalf("Before(%v) = %v, want %v (60 days earlier)", ref, cutoff, wantCutoff)
+	}
+	if got := ref.Sub(cutoff); got < 59*24*time.Hour || got > 60*24*time.Hour {
+		t.Fatalf("60d cutoff is %v away from reference, want approximately 60 days", got)
+	}
+}
+
+func TestDurationBefore(t *testing.T) {
+	ref := time.Date(2026, 7, 27, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		d    Duration
+		want time.Time
+	}{
+		{Duration{Amount: 7, Unit: Days}, ref.AddDate(0, 0, -7)},
+		{Duration{Amount: 2, Unit: Weeks}, ref.AddDate(0, 0, -14)},
+		{Duration{Amount: 3, Unit: Months}, ref.AddDate(0, -3, 0)},
+		{Duration{Amount: 1, Unit: Years}, ref.AddDate(-1, 0, 0)},
+	}
+	for _, tc := range testCases {
+		if got := tc.d.Before(ref); !got.Equal(tc.want) {
+			t.Errorf("%+v.Before(%v) = %v, want %v", tc.d, ref, got, tc.want)
+		}
+	}
+}